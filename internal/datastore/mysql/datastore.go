@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/authzed/spicedb/internal/datastore/mysql/migrations"
+)
+
+// Datastore wires Open and NewQueryBuilder together into a single
+// long-lived object: Open establishes the *sql.DB with connection
+// keepalive/health-probing applied, and NewQueryBuilder builds the
+// parameterized queries (including the batched tuple writers) run against
+// it.
+type Datastore struct {
+	db      *sql.DB
+	queries *QueryBuilder
+
+	// cancelHealthProbe stops the health probe goroutine started by Open.
+	cancelHealthProbe context.CancelFunc
+}
+
+// NewDatastore opens a MySQL connection pool for dsn, applying
+// healthOpts, and builds the query set driver needs, threading
+// writeBatchSize into the batched namespace/tuple/caveat writers.
+func NewDatastore(ctx context.Context, dsn string, driver *migrations.MySQLDriver, writeBatchSize int, healthOpts ...ConnectionHealthOption) (*Datastore, error) {
+	probeCtx, cancel := context.WithCancel(ctx)
+	db, err := Open(probeCtx, dsn, healthOpts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &Datastore{
+		db:                db,
+		queries:           NewQueryBuilder(driver, writeBatchSize),
+		cancelHealthProbe: cancel,
+	}, nil
+}
+
+// Close stops the health probe and releases the underlying connection pool.
+func (ds *Datastore) Close() error {
+	if ds.cancelHealthProbe != nil {
+		ds.cancelHealthProbe()
+	}
+	return ds.db.Close()
+}
+
+// WriteRelationships inserts rows as new relationships, batching them into
+// as few multi-row INSERTs as BuildBatchedTupleWrites allows.
+func (ds *Datastore) WriteRelationships(ctx context.Context, rows []TupleRow) error {
+	return ds.execBatchedTupleWrites(ctx, ds.queries.BatchWriteTupleQuery, rows)
+}
+
+// TouchRelationships writes rows as relationships, overwriting any
+// existing caveat and transaction markers in place via upsertTuple's ON
+// DUPLICATE KEY UPDATE clause (TOUCH semantics).
+func (ds *Datastore) TouchRelationships(ctx context.Context, rows []TupleRow) error {
+	return ds.execBatchedTupleWrites(ctx, ds.queries.UpsertTupleQuery, rows)
+}
+
+func (ds *Datastore) execBatchedTupleWrites(ctx context.Context, builder *batchedInsertBuilder, rows []TupleRow) error {
+	for _, query := range BuildBatchedTupleWrites(builder, rows, 0) {
+		stmt, args, err := query.ToSql()
+		if err != nil {
+			return fmt.Errorf("unable to build batched tuple write: %w", err)
+		}
+		if _, err := ds.db.ExecContext(ctx, stmt, args...); err != nil {
+			return fmt.Errorf("unable to execute batched tuple write: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a minimal database/sql driver that records the statements
+// executed against it, standing in for a real MySQL connection so
+// Datastore's write path can be exercised without a live database.
+type fakeDriver struct {
+	mu          sync.Mutex
+	executedSQL []string
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct{ driver *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported by fakeDriver") }
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.driver.mu.Lock()
+	s.conn.driver.executedSQL = append(s.conn.driver.executedSQL, s.query)
+	s.conn.driver.mu.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("queries not supported by fakeDriver")
+}
+
+var fakeDriverCounter int64
+
+func newTestDatastore(t *testing.T, batchSize int) (*Datastore, *fakeDriver) {
+	t.Helper()
+
+	fd := &fakeDriver{}
+	name := fmt.Sprintf("fakemysql-%d", atomic.AddInt64(&fakeDriverCounter, 1))
+	sql.Register(name, fd)
+
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	tupleColumns := []string{"namespace", "object_id", "relation", "userset_namespace", "userset_object_id", "userset_relation", "caveat_name", "caveat_context", "created_transaction"}
+
+	return &Datastore{
+		db: db,
+		queries: &QueryBuilder{
+			BatchWriteTupleQuery: newBatchedInsertBuilder(func() sq.InsertBuilder {
+				return sq.Insert("tuple").Columns(tupleColumns...)
+			}, batchSize),
+			UpsertTupleQuery: newBatchedInsertBuilder(func() sq.InsertBuilder {
+				return sq.Insert("tuple").Columns(tupleColumns...).Suffix("ON DUPLICATE KEY UPDATE caveat_name = VALUES(caveat_name)")
+			}, batchSize),
+		},
+	}, fd
+}
+
+func TestDatastoreWriteRelationshipsExecutesBatchedInserts(t *testing.T) {
+	ds, fd := newTestDatastore(t, 2)
+
+	rows := []TupleRow{
+		{Namespace: "document", ObjectID: "1", Relation: "viewer", CreatedTxn: 1},
+		{Namespace: "document", ObjectID: "2", Relation: "viewer", CreatedTxn: 1},
+		{Namespace: "document", ObjectID: "3", Relation: "viewer", CreatedTxn: 1},
+	}
+
+	err := ds.WriteRelationships(context.Background(), rows)
+	require.NoError(t, err)
+
+	// Two rows batch together and the trailing row flushes on its own.
+	require.Len(t, fd.executedSQL, 2)
+}
+
+func TestDatastoreTouchRelationshipsUsesUpsertQuery(t *testing.T) {
+	ds, fd := newTestDatastore(t, 100)
+
+	rows := []TupleRow{
+		{Namespace: "document", ObjectID: "1", Relation: "viewer", CreatedTxn: 1},
+	}
+
+	err := ds.TouchRelationships(context.Background(), rows)
+	require.NoError(t, err)
+
+	require.Len(t, fd.executedSQL, 1)
+	require.Contains(t, fd.executedSQL[0], "ON DUPLICATE KEY UPDATE")
+}
@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/authzed/spicedb/internal/logging"
+)
+
+const (
+	// defaultConnMaxIdleTime bounds how long a pooled connection may sit
+	// idle before database/sql closes it, so connections behind a
+	// load balancer or ProxySQL don't outlive the middlebox's own idle
+	// timeout and get silently dropped.
+	defaultConnMaxIdleTime = 30 * time.Minute
+
+	// defaultConnMaxLifetime bounds the total lifetime of a pooled
+	// connection, forcing periodic reconnection so long-lived
+	// connections can't accumulate stale routing state.
+	defaultConnMaxLifetime = time.Hour
+
+	// defaultHealthCheckInterval is how often the health prober pings
+	// the backend between normal query traffic. Zero disables probing.
+	defaultHealthCheckInterval = 30 * time.Second
+)
+
+var healthCheckProbeFailures = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "spicedb",
+	Subsystem: "datastore",
+	Name:      "mysql_health_check_failures_total",
+	Help:      "The number of times the MySQL connection health probe has failed to reach the backend.",
+})
+
+func init() {
+	prometheus.MustRegister(healthCheckProbeFailures)
+}
+
+// connectionHealthOptions configures the keepalive and liveness-probing
+// behavior of a MySQL *sql.DB, analogous to the DialKeepAliveTime /
+// PermitWithoutStream knobs on the gRPC and etcd clients: it lets idle
+// connections be recycled before a middlebox kills them out from under us,
+// and surfaces backend flapping before it shows up as query latency.
+type connectionHealthOptions struct {
+	connMaxIdleTime     time.Duration
+	connMaxLifetime     time.Duration
+	healthCheckInterval time.Duration
+}
+
+func defaultConnectionHealthOptions() connectionHealthOptions {
+	return connectionHealthOptions{
+		connMaxIdleTime:     defaultConnMaxIdleTime,
+		connMaxLifetime:     defaultConnMaxLifetime,
+		healthCheckInterval: defaultHealthCheckInterval,
+	}
+}
+
+// ConnectionHealthOption configures the keepalive behavior applied by
+// ConfigureConnectionHealth. NewDatastore passes its healthOpts through
+// Open down to the underlying *sql.DB at open time.
+type ConnectionHealthOption func(*connectionHealthOptions)
+
+// WithConnMaxIdleTime sets the maximum amount of time a pooled connection
+// may remain idle before it is closed and recycled.
+func WithConnMaxIdleTime(d time.Duration) ConnectionHealthOption {
+	return func(o *connectionHealthOptions) { o.connMaxIdleTime = d }
+}
+
+// WithConnMaxLifetime sets the maximum amount of time a pooled connection
+// may be reused before it is closed and recycled.
+func WithConnMaxLifetime(d time.Duration) ConnectionHealthOption {
+	return func(o *connectionHealthOptions) { o.connMaxLifetime = d }
+}
+
+// WithHealthCheckInterval sets how often the background prober pings the
+// backend with a `SELECT 1`. An interval of zero disables probing.
+func WithHealthCheckInterval(d time.Duration) ConnectionHealthOption {
+	return func(o *connectionHealthOptions) { o.healthCheckInterval = d }
+}
+
+// ConfigureConnectionHealth applies connection keepalive settings to db
+// and, unless probing has been disabled via WithHealthCheckInterval(0),
+// starts a background goroutine that periodically probes the backend
+// until ctx is canceled. Callers own stopping the probe by canceling ctx;
+// a Datastore built by NewDatastore cancels it in Close().
+func ConfigureConnectionHealth(ctx context.Context, db *sql.DB, options ...ConnectionHealthOption) {
+	opts := defaultConnectionHealthOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+
+	db.SetConnMaxIdleTime(opts.connMaxIdleTime)
+	db.SetConnMaxLifetime(opts.connMaxLifetime)
+
+	if opts.healthCheckInterval <= 0 {
+		return
+	}
+
+	go runHealthCheckProbe(ctx, db, opts.healthCheckInterval)
+}
+
+// Open opens the *sql.DB backing the MySQL datastore for the given DSN and
+// applies the keepalive and health-probe options from opts, so that idle
+// connections and backend flapping are handled from the moment the pool
+// is created rather than left to database/sql's defaults. The health
+// probe goroutine (if enabled) runs until ctx is canceled; NewDatastore
+// passes a context that Datastore.Close cancels.
+func Open(ctx context.Context, dsn string, opts ...ConnectionHealthOption) (*sql.DB, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open mysql connection pool: %w", err)
+	}
+
+	ConfigureConnectionHealth(ctx, db, opts...)
+
+	return db, nil
+}
+
+// runHealthCheckProbe issues a `SELECT 1` against db on every tick until
+// ctx is canceled, incrementing healthCheckProbeFailures whenever the
+// probe fails so operators can alert on backend flapping before it shows
+// up as check latency.
+func runHealthCheckProbe(ctx context.Context, db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			probeCtx, cancel := context.WithTimeout(ctx, interval)
+			_, err := db.ExecContext(probeCtx, "SELECT 1")
+			cancel()
+			if err != nil {
+				healthCheckProbeFailures.Inc()
+				logging.Ctx(ctx).Warn().Err(err).Msg("mysql connection health probe failed")
+			}
+		}
+	}
+}
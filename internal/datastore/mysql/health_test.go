@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionHealthOptionsDefaults(t *testing.T) {
+	opts := defaultConnectionHealthOptions()
+	require.Equal(t, defaultConnMaxIdleTime, opts.connMaxIdleTime)
+	require.Equal(t, defaultConnMaxLifetime, opts.connMaxLifetime)
+	require.Equal(t, defaultHealthCheckInterval, opts.healthCheckInterval)
+}
+
+func TestConnectionHealthOptionsOverrides(t *testing.T) {
+	opts := defaultConnectionHealthOptions()
+	WithConnMaxIdleTime(time.Minute)(&opts)
+	WithConnMaxLifetime(2 * time.Minute)(&opts)
+	WithHealthCheckInterval(0)(&opts)
+
+	require.Equal(t, time.Minute, opts.connMaxIdleTime)
+	require.Equal(t, 2*time.Minute, opts.connMaxLifetime)
+	require.Equal(t, time.Duration(0), opts.healthCheckInterval)
+}
+
+func TestOpenAcceptsConnectionHealthOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db, err := Open(ctx, "user:pass@tcp(127.0.0.1:3306)/spicedb", WithHealthCheckInterval(0))
+	require.NoError(t, err)
+	defer db.Close()
+}
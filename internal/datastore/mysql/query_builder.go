@@ -1,11 +1,26 @@
 package mysql
 
 import (
+	"sync"
+
 	"github.com/authzed/spicedb/internal/datastore/mysql/migrations"
 
 	sq "github.com/Masterminds/squirrel"
 )
 
+// defaultWriteBatchSize is the default number of rows a batchedInsertBuilder
+// buffers before flushing a single multi-row INSERT, balancing transaction
+// overhead against the risk of exceeding max_allowed_packet. Callers can
+// override it via NewQueryBuilder's writeBatchSize parameter.
+const defaultWriteBatchSize = 100
+
+// defaultMaxBatchedCaveatContextBytes bounds how large a single
+// relationship's caveat context may be before BuildBatchedTupleWrites
+// flushes it (and anything already buffered) immediately, rather than
+// risking a handful of large contexts pushing a multi-row INSERT over
+// MySQL's max_allowed_packet.
+const defaultMaxBatchedCaveatContextBytes = 64 * 1024
+
 // QueryBuilder captures all parameterizable queries used
 // by the MySQL datastore implementation
 type QueryBuilder struct {
@@ -13,6 +28,7 @@ type QueryBuilder struct {
 	LoadRevisionRange sq.SelectBuilder
 
 	WriteNamespaceQuery        sq.InsertBuilder
+	BatchWriteNamespaceQuery   *batchedInsertBuilder
 	ReadNamespaceQuery         sq.SelectBuilder
 	DeleteNamespaceQuery       sq.UpdateBuilder
 	DeleteNamespaceTuplesQuery sq.UpdateBuilder
@@ -27,18 +43,28 @@ type QueryBuilder struct {
 	DeleteTupleQuery             sq.UpdateBuilder
 	QueryRelationshipExistsQuery sq.SelectBuilder
 	WriteTupleQuery              sq.InsertBuilder
+	BatchWriteTupleQuery         *batchedInsertBuilder
+	UpsertTupleQuery             *batchedInsertBuilder
 	QueryChangedQuery            sq.SelectBuilder
 	CountTupleQuery              sq.SelectBuilder
 
-	WriteCaveatQuery  sq.InsertBuilder
-	ReadCaveatQuery   sq.SelectBuilder
-	ListCaveatsQuery  sq.SelectBuilder
-	DeleteCaveatQuery sq.UpdateBuilder
+	WriteCaveatQuery      sq.InsertBuilder
+	BatchWriteCaveatQuery *batchedInsertBuilder
+	ReadCaveatQuery       sq.SelectBuilder
+	ListCaveatsQuery      sq.SelectBuilder
+	DeleteCaveatQuery     sq.UpdateBuilder
 }
 
 // NewQueryBuilder returns a new QueryBuilder instance. The migration
-// driver is used to determine the names of the tables.
-func NewQueryBuilder(driver *migrations.MySQLDriver) *QueryBuilder {
+// driver is used to determine the names of the tables. writeBatchSize
+// controls how many rows BatchWriteNamespaceQuery, BatchWriteTupleQuery,
+// UpsertTupleQuery, and BatchWriteCaveatQuery accumulate before flushing a
+// multi-row INSERT; a value <= 0 falls back to defaultWriteBatchSize.
+func NewQueryBuilder(driver *migrations.MySQLDriver, writeBatchSize int) *QueryBuilder {
+	if writeBatchSize <= 0 {
+		writeBatchSize = defaultWriteBatchSize
+	}
+
 	builder := QueryBuilder{}
 
 	// transaction builders
@@ -47,6 +73,7 @@ func NewQueryBuilder(driver *migrations.MySQLDriver) *QueryBuilder {
 
 	// namespace builders
 	builder.WriteNamespaceQuery = writeNamespace(driver.Namespace())
+	builder.BatchWriteNamespaceQuery = batchWriteNamespace(driver.Namespace(), writeBatchSize)
 	builder.ReadNamespaceQuery = readNamespace(driver.Namespace())
 	builder.DeleteNamespaceQuery = deleteNamespace(driver.Namespace())
 
@@ -63,6 +90,8 @@ func NewQueryBuilder(driver *migrations.MySQLDriver) *QueryBuilder {
 	builder.DeleteTupleQuery = deleteTuple(driver.RelationTuple())
 	builder.QueryRelationshipExistsQuery = queryRelationshipExists(driver.RelationTuple())
 	builder.WriteTupleQuery = writeTuple(driver.RelationTuple())
+	builder.BatchWriteTupleQuery = batchWriteTuple(driver.RelationTuple(), writeBatchSize)
+	builder.UpsertTupleQuery = upsertTuple(driver.RelationTuple(), writeBatchSize)
 	builder.QueryChangedQuery = queryChanged(driver.RelationTuple())
 	builder.CountTupleQuery = countRels(driver.RelationTuple())
 
@@ -70,6 +99,7 @@ func NewQueryBuilder(driver *migrations.MySQLDriver) *QueryBuilder {
 	builder.ReadCaveatQuery = readCaveat(driver.Caveat())
 	builder.ListCaveatsQuery = listCaveats(driver.Caveat())
 	builder.WriteCaveatQuery = writeCaveat(driver.Caveat())
+	builder.BatchWriteCaveatQuery = batchWriteCaveat(driver.Caveat(), writeBatchSize)
 	builder.DeleteCaveatQuery = deleteCaveat(driver.Caveat())
 
 	return &builder
@@ -91,6 +121,10 @@ func writeCaveat(tableCaveat string) sq.InsertBuilder {
 	)
 }
 
+func batchWriteCaveat(tableCaveat string, n int) *batchedInsertBuilder {
+	return newBatchedInsertBuilder(func() sq.InsertBuilder { return writeCaveat(tableCaveat) }, n)
+}
+
 func readCaveat(tableCaveat string) sq.SelectBuilder {
 	return sb.Select(colCaveatDefinition, colCreatedTxn).From(tableCaveat)
 }
@@ -138,6 +172,10 @@ func writeNamespace(tableNamespace string) sq.InsertBuilder {
 	)
 }
 
+func batchWriteNamespace(tableNamespace string, n int) *batchedInsertBuilder {
+	return newBatchedInsertBuilder(func() sq.InsertBuilder { return writeNamespace(tableNamespace) }, n)
+}
+
 func readNamespace(tableNamespace string) sq.SelectBuilder {
 	return sb.Select(colConfig, colCreatedTxn).From(tableNamespace)
 }
@@ -205,6 +243,141 @@ func writeTuple(tableTuple string) sq.InsertBuilder {
 	)
 }
 
+func batchWriteTuple(tableTuple string, n int) *batchedInsertBuilder {
+	return newBatchedInsertBuilder(func() sq.InsertBuilder { return writeTuple(tableTuple) }, n)
+}
+
+// upsertTuple is writeTuple's ON DUPLICATE KEY UPDATE variant, letting
+// TOUCH semantics overwrite an existing relationship's caveat and
+// transaction markers in place, instead of requiring a read to detect the
+// conflict before writing.
+func upsertTuple(tableTuple string, n int) *batchedInsertBuilder {
+	return newBatchedInsertBuilder(func() sq.InsertBuilder {
+		return writeTuple(tableTuple).Suffix(
+			"ON DUPLICATE KEY UPDATE " +
+				colCaveatName + " = VALUES(" + colCaveatName + "), " +
+				colCaveatContext + " = VALUES(" + colCaveatContext + "), " +
+				colCreatedTxn + " = VALUES(" + colCreatedTxn + ")",
+		)
+	}, n)
+}
+
+// batchedInsertBuilder buffers rows until batchSize of them have
+// accumulated, then produces a single multi-row INSERT spanning the whole
+// batch. This amortizes per-statement transaction overhead across many
+// relationship/namespace/caveat writes instead of round-tripping once per
+// row. QueryBuilder's batched fields are built once and shared across the
+// datastore's concurrent writers, so Add/Flush guard the row buffer with
+// a mutex.
+type batchedInsertBuilder struct {
+	newQuery  func() sq.InsertBuilder
+	batchSize int
+
+	mu   sync.Mutex
+	rows [][]any
+}
+
+func newBatchedInsertBuilder(newQuery func() sq.InsertBuilder, batchSize int) *batchedInsertBuilder {
+	return &batchedInsertBuilder{newQuery: newQuery, batchSize: batchSize}
+}
+
+// Add buffers row and reports false until batchSize rows have accumulated,
+// at which point it returns the multi-row INSERT for the whole batch and
+// resets the buffer. Passing flush=true forces an immediate single-row
+// (or partial-batch) INSERT, for callers that need to fall back to
+// smaller writes, e.g. because a relationship's caveat context is large
+// enough to risk exceeding max_allowed_packet. Safe for concurrent use.
+func (b *batchedInsertBuilder) Add(flush bool, row ...any) (sq.InsertBuilder, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rows = append(b.rows, row)
+	if !flush && len(b.rows) < b.batchSize {
+		return sq.InsertBuilder{}, false
+	}
+
+	return b.flushLocked()
+}
+
+// Flush forces immediate emission of any rows buffered by Add as a single
+// (possibly partial) multi-row INSERT, without requiring a further row to
+// trigger it. It reports false if nothing was buffered. Safe for
+// concurrent use.
+func (b *batchedInsertBuilder) Flush() (sq.InsertBuilder, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flushLocked()
+}
+
+// flushLocked builds the multi-row INSERT for whatever is currently
+// buffered and resets the buffer. Callers must hold b.mu.
+func (b *batchedInsertBuilder) flushLocked() (sq.InsertBuilder, bool) {
+	if len(b.rows) == 0 {
+		return sq.InsertBuilder{}, false
+	}
+
+	query := b.newQuery()
+	for _, buffered := range b.rows {
+		query = query.Values(buffered...)
+	}
+	b.rows = b.rows[:0]
+
+	return query, true
+}
+
+// TupleRow is a single relationship destined for BatchWriteTupleQuery or
+// UpsertTupleQuery, in the column order writeTuple expects.
+type TupleRow struct {
+	Namespace        string
+	ObjectID         string
+	Relation         string
+	UsersetNamespace string
+	UsersetObjectID  string
+	UsersetRelation  string
+	CaveatName       string
+	CaveatContext    []byte
+	CreatedTxn       uint64
+}
+
+// BuildBatchedTupleWrites feeds rows through builder (typically
+// QueryBuilder.BatchWriteTupleQuery or .UpsertTupleQuery) and returns the
+// resulting multi-row INSERT statements. A row whose caveat context
+// exceeds maxCaveatContextBytes (defaultMaxBatchedCaveatContextBytes if
+// <= 0) forces an immediate flush of that row, and anything already
+// buffered, as its own statement, rather than risking a handful of large
+// contexts pushing a batch over MySQL's max_allowed_packet.
+func BuildBatchedTupleWrites(builder *batchedInsertBuilder, rows []TupleRow, maxCaveatContextBytes int) []sq.InsertBuilder {
+	if maxCaveatContextBytes <= 0 {
+		maxCaveatContextBytes = defaultMaxBatchedCaveatContextBytes
+	}
+
+	var queries []sq.InsertBuilder
+	for _, row := range rows {
+		flush := len(row.CaveatContext) > maxCaveatContextBytes
+		query, ready := builder.Add(flush,
+			row.Namespace,
+			row.ObjectID,
+			row.Relation,
+			row.UsersetNamespace,
+			row.UsersetObjectID,
+			row.UsersetRelation,
+			row.CaveatName,
+			row.CaveatContext,
+			row.CreatedTxn,
+		)
+		if ready {
+			queries = append(queries, query)
+		}
+	}
+
+	if query, ready := builder.Flush(); ready {
+		queries = append(queries, query)
+	}
+
+	return queries
+}
+
 func queryChanged(tableTuple string) sq.SelectBuilder {
 	return sb.Select(
 		colNamespace,
@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"sync"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBatchedInsertBuilder(batchSize int) *batchedInsertBuilder {
+	return newBatchedInsertBuilder(func() sq.InsertBuilder {
+		return sq.Insert("tuple").Columns("namespace", "object_id", "relation", "userset_namespace", "userset_object_id", "userset_relation", "caveat_name", "caveat_context", "created_transaction")
+	}, batchSize)
+}
+
+func TestBatchedInsertBuilderFlushesAtBatchSize(t *testing.T) {
+	b := newTestBatchedInsertBuilder(2)
+
+	_, ready := b.Add(false, "ns", "obj1", "viewer", "", "", "", "", nil, uint64(1))
+	require.False(t, ready, "should not flush before batchSize rows are buffered")
+
+	query, ready := b.Add(false, "ns", "obj2", "viewer", "", "", "", "", nil, uint64(1))
+	require.True(t, ready)
+
+	_, args, err := query.ToSql()
+	require.NoError(t, err)
+	require.Len(t, args, 2*9, "both buffered rows should appear as value tuples in the flushed INSERT")
+}
+
+func TestBatchedInsertBuilderForceFlush(t *testing.T) {
+	b := newTestBatchedInsertBuilder(100)
+
+	_, ready := b.Add(false, "ns", "obj1", "viewer", "", "", "", "", nil, uint64(1))
+	require.False(t, ready)
+
+	query, ready := b.Add(true, "ns", "obj2", "viewer", "", "", "", "", nil, uint64(1))
+	require.True(t, ready, "flush=true should emit immediately regardless of batch size")
+
+	_, _, err := query.ToSql()
+	require.NoError(t, err)
+}
+
+func TestBatchedInsertBuilderFlush(t *testing.T) {
+	b := newTestBatchedInsertBuilder(100)
+
+	_, ready := b.Flush()
+	require.False(t, ready, "flushing an empty builder should report nothing to do")
+
+	_, ready = b.Add(false, "ns", "obj1", "viewer", "", "", "", "", nil, uint64(1))
+	require.False(t, ready)
+
+	_, ready = b.Flush()
+	require.True(t, ready, "flush should emit whatever was buffered even under batchSize")
+
+	_, ready = b.Flush()
+	require.False(t, ready, "flush should be idempotent once drained")
+}
+
+func TestBuildBatchedTupleWritesFallsBackOnLargeCaveatContext(t *testing.T) {
+	b := newTestBatchedInsertBuilder(100)
+
+	smallContext := []byte("{}")
+	largeContext := make([]byte, 128)
+
+	rows := []TupleRow{
+		{Namespace: "document", ObjectID: "1", Relation: "viewer", CaveatContext: smallContext},
+		{Namespace: "document", ObjectID: "2", Relation: "viewer", CaveatContext: largeContext},
+		{Namespace: "document", ObjectID: "3", Relation: "viewer", CaveatContext: smallContext},
+	}
+
+	queries := BuildBatchedTupleWrites(b, rows, 64)
+
+	// The oversized row forces its own flush (containing rows 1 and 2,
+	// since row 1 was already buffered), and the trailing Flush emits the
+	// remaining row 3 as a second statement.
+	require.Len(t, queries, 2)
+}
+
+func TestBuildBatchedTupleWritesRespectsDefaultMaxCaveatContextBytes(t *testing.T) {
+	b := newTestBatchedInsertBuilder(100)
+
+	rows := []TupleRow{
+		{Namespace: "document", ObjectID: "1", Relation: "viewer", CaveatContext: []byte("{}")},
+	}
+
+	queries := BuildBatchedTupleWrites(b, rows, 0)
+	require.Len(t, queries, 1, "a single small row should flush once at the end, using the default threshold")
+}
+
+// TestBatchedInsertBuilderConcurrentAdd exercises Add from many goroutines
+// at once against a single shared builder, the way QueryBuilder's batched
+// fields are shared across the datastore's concurrent writers. Run with
+// -race to confirm the row buffer is properly guarded.
+func TestBatchedInsertBuilderConcurrentAdd(t *testing.T) {
+	const goroutines = 20
+	const rowsPerGoroutine = 50
+
+	b := newTestBatchedInsertBuilder(7)
+
+	var (
+		wg               sync.WaitGroup
+		mu               sync.Mutex
+		totalFlushedRows int
+	)
+
+	countRows := func(query sq.InsertBuilder) int {
+		_, args, err := query.ToSql()
+		require.NoError(t, err)
+		return len(args) / 9
+	}
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < rowsPerGoroutine; j++ {
+				query, ready := b.Add(false, "ns", "obj", "viewer", "", "", "", "", nil, uint64(n))
+				if ready {
+					mu.Lock()
+					totalFlushedRows += countRows(query)
+					mu.Unlock()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if query, ready := b.Flush(); ready {
+		totalFlushedRows += countRows(query)
+	}
+
+	require.Equal(t, goroutines*rowsPerGoroutine, totalFlushedRows, "every Add call should be reflected exactly once across the flushed batches")
+}
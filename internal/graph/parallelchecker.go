@@ -2,7 +2,9 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
@@ -14,43 +16,128 @@ import (
 	"github.com/authzed/spicedb/pkg/tuple"
 )
 
+// ErrCheckerCancelled is returned by QueueCheck when the checker's context
+// has already been cancelled, either by the caller, a failed liveness
+// probe, or the results threshold having been reached.
+var ErrCheckerCancelled = errors.New("parallel checker has already been cancelled")
+
+// Option is a function that modifies a ParallelChecker at construction
+// time.
+type Option func(*ParallelChecker)
+
+// WithLivenessProbe configures the checker to periodically invoke isAlive
+// at the given interval, cancelling outstanding work as soon as isAlive
+// returns an error. This is intended to let callers detect a disconnected
+// client (e.g. by probing the associated gRPC stream's context) without
+// waiting for the entire reachability walk to complete.
+func WithLivenessProbe(interval time.Duration, isAlive func() error) Option {
+	return func(pc *ParallelChecker) {
+		pc.pollInterval = interval
+		pc.isAlive = isAlive
+	}
+}
+
+// WithResultsThreshold configures the checker to stop dispatching new
+// checks and return early from Finish once the number of found results
+// reaches threshold. A threshold of 0 disables early termination.
+func WithResultsThreshold(threshold uint64) Option {
+	return func(pc *ParallelChecker) {
+		pc.resultsThreshold = threshold
+	}
+}
+
 type ParallelChecker struct {
 	toCheck       chan *v1.DispatchCheckRequest
 	c             dispatch.Check
 	g             *errgroup.Group
 	checkCtx      context.Context
+	cancel        context.CancelFunc
 	subject       *core.ObjectAndRelation
 	maxConcurrent uint8
 	results       *tuple.ONRSet
 	mu            sync.Mutex
+
+	pollInterval     time.Duration
+	isAlive          func() error
+	resultsThreshold uint64
+	stoppedEarly     bool
+	probeDone        chan struct{}
 }
 
-func NewParallelChecker(ctx context.Context, c dispatch.Check, subject *core.ObjectAndRelation, maxConcurrent uint8) *ParallelChecker {
-	g, checkCtx := errgroup.WithContext(ctx)
+func NewParallelChecker(ctx context.Context, c dispatch.Check, subject *core.ObjectAndRelation, maxConcurrent uint8, opts ...Option) *ParallelChecker {
+	cancelCtx, cancel := context.WithCancel(ctx)
+	g, checkCtx := errgroup.WithContext(cancelCtx)
 	toCheck := make(chan *v1.DispatchCheckRequest)
-	return &ParallelChecker{toCheck, c, g, checkCtx, subject, maxConcurrent, tuple.NewONRSet(), sync.Mutex{}}
+
+	pc := &ParallelChecker{
+		toCheck:       toCheck,
+		c:             c,
+		g:             g,
+		checkCtx:      checkCtx,
+		cancel:        cancel,
+		subject:       subject,
+		maxConcurrent: maxConcurrent,
+		results:       tuple.NewONRSet(),
+		probeDone:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(pc)
+	}
+
+	return pc
 }
 
 func (pc *ParallelChecker) AddResult(resource *core.ObjectAndRelation) {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
+
 	pc.results.Add(resource)
+	if pc.resultsThreshold > 0 && uint64(pc.results.Length()) >= pc.resultsThreshold {
+		pc.stopEarly()
+	}
+}
+
+// stopEarly cancels outstanding dispatched work without failing Finish.
+// Callers must hold pc.mu.
+func (pc *ParallelChecker) stopEarly() {
+	pc.stoppedEarly = true
+	pc.cancel()
 }
 
-func (pc *ParallelChecker) QueueCheck(resource *core.ObjectAndRelation, meta *v1.ResolverMeta) {
-	pc.toCheck <- &v1.DispatchCheckRequest{
+// QueueCheck enqueues a check for dispatch. It does not block on a full
+// input channel once the checker has been cancelled, instead returning
+// ErrCheckerCancelled so producers don't deadlock racing early
+// cancellation.
+func (pc *ParallelChecker) QueueCheck(resource *core.ObjectAndRelation, meta *v1.ResolverMeta) error {
+	select {
+	case <-pc.checkCtx.Done():
+		return ErrCheckerCancelled
+	default:
+	}
+
+	select {
+	case pc.toCheck <- &v1.DispatchCheckRequest{
 		Metadata:          meta,
 		ObjectAndRelation: resource,
 		Subject:           pc.subject,
+	}:
+		return nil
+	case <-pc.checkCtx.Done():
+		return ErrCheckerCancelled
 	}
 }
 
 func (pc *ParallelChecker) Start() {
+	if pc.pollInterval > 0 && pc.isAlive != nil {
+		go pc.runLivenessProbe()
+	}
+
 	pc.g.Go(func() error {
 		sem := semaphore.NewWeighted(int64(pc.maxConcurrent))
 		for {
 			if err := sem.Acquire(pc.checkCtx, 1); err != nil {
-				return err
+				return pc.wrapCancellation(err)
 			}
 			req, ok := <-pc.toCheck
 			if !ok {
@@ -62,7 +149,7 @@ func (pc *ParallelChecker) Start() {
 				defer sem.Release(1)
 				res, err := pc.c.DispatchCheck(pc.checkCtx, req)
 				if err != nil {
-					return err
+					return pc.wrapCancellation(err)
 				}
 				if res.Membership == v1.DispatchCheckResponse_MEMBER {
 					pc.AddResult(req.ObjectAndRelation)
@@ -71,14 +158,56 @@ func (pc *ParallelChecker) Start() {
 			})
 		}
 		if err := sem.Acquire(pc.checkCtx, int64(pc.maxConcurrent)); err != nil {
-			return err
+			return pc.wrapCancellation(err)
 		}
 		return nil
 	})
 }
 
+// runLivenessProbe periodically invokes isAlive until pc.probeDone is
+// closed by Finish or pc.checkCtx is done. It deliberately runs outside
+// pc.g: errgroup only cancels checkCtx once all of its goroutines have
+// returned, so a probe whose only exit path was checkCtx.Done() would
+// wait on g.Wait() while g.Wait() waited on it, deadlocking Finish on the
+// common path where the check completes normally and nothing ever calls
+// stopEarly.
+func (pc *ParallelChecker) runLivenessProbe() {
+	ticker := time.NewTicker(pc.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pc.probeDone:
+			return
+		case <-pc.checkCtx.Done():
+			return
+		case <-ticker.C:
+			if err := pc.isAlive(); err != nil {
+				pc.mu.Lock()
+				pc.stopEarly()
+				pc.mu.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// wrapCancellation swallows context cancellation errors that were caused by
+// a deliberate early stop (results threshold reached or client
+// disconnected), since those are expected outcomes rather than failures.
+func (pc *ParallelChecker) wrapCancellation(err error) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.stoppedEarly && errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
 func (pc *ParallelChecker) Finish() (*tuple.ONRSet, error) {
 	close(pc.toCheck)
+	close(pc.probeDone)
 	if err := pc.g.Wait(); err != nil {
 		return nil, err
 	}
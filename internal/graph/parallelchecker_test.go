@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	v1 "github.com/authzed/spicedb/pkg/proto/dispatch/v1"
+)
+
+type fakeChecker struct{}
+
+func (fakeChecker) DispatchCheck(_ context.Context, req *v1.DispatchCheckRequest) (*v1.DispatchCheckResponse, error) {
+	return &v1.DispatchCheckResponse{Membership: v1.DispatchCheckResponse_NOT_MEMBER}, nil
+}
+
+// TestFinishWithLivenessProbeDoesNotDeadlock ensures that a checker
+// configured with WithLivenessProbe can still complete normally: the probe
+// goroutine must not hold Finish's errgroup.Wait hostage once there's no
+// more work and nothing has triggered an early stop.
+func TestFinishWithLivenessProbeDoesNotDeadlock(t *testing.T) {
+	pc := NewParallelChecker(
+		context.Background(),
+		fakeChecker{},
+		&core.ObjectAndRelation{Namespace: "document", ObjectId: "1", Relation: "viewer"},
+		10,
+		WithLivenessProbe(time.Millisecond, func() error { return nil }),
+	)
+	pc.Start()
+
+	require.NoError(t, pc.QueueCheck(&core.ObjectAndRelation{Namespace: "document", ObjectId: "2", Relation: "viewer"}, &v1.ResolverMeta{}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := pc.Finish()
+		require.NoError(t, err)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Finish() deadlocked with a liveness probe configured")
+	}
+}
@@ -1,8 +1,11 @@
 package compiler
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
@@ -13,6 +16,12 @@ import (
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 )
 
+// defaultImportTimeout bounds how long Compile will wait on
+// ImportResolver.Resolve calls in total before giving up, so a slow or
+// unreachable remote import (e.g. a hung HTTPS server) can't hang a
+// compile indefinitely. Callers can override it via ImportTimeout.
+const defaultImportTimeout = 30 * time.Second
+
 // InputSchema defines the input for a Compile.
 type InputSchema struct {
 	// Source is the source of the schema being compiled.
@@ -56,6 +65,18 @@ type config struct {
 	// In an import context, this is the folder containing
 	// the importing schema (as opposed to imported schemas)
 	sourceFolder string
+	// importResolver resolves non-relative import paths (file://, https://,
+	// git+https://) to their schema contents. If nil, only SourceFolder-relative
+	// imports are supported.
+	importResolver ImportResolver
+	// vendorDir, if set, causes Compile to write the root schema and every
+	// transitively resolved import into this directory, with their import
+	// statements rewritten to point at the vendored copies, so the result can
+	// be recompiled offline.
+	vendorDir *string
+	// importTimeout bounds how long resolving remote imports may take in
+	// total. Defaults to defaultImportTimeout.
+	importTimeout time.Duration
 }
 
 func SkipValidation() Option { return func(cfg *config) { cfg.skipValidation = true } }
@@ -82,6 +103,30 @@ func SourceFolder(sourceFolder string) Option {
 	return func(cfg *config) { cfg.sourceFolder = sourceFolder }
 }
 
+// ImportsVia configures the ImportResolver used to resolve any import path
+// that isn't a plain SourceFolder-relative file reference, e.g.
+// `import "https://example.com/schema.zed#sha256=..."` or
+// `import "git+https://github.com/org/repo.git@v1/schema.zed"`.
+func ImportsVia(resolver ImportResolver) Option {
+	return func(cfg *config) { cfg.importResolver = resolver }
+}
+
+// VendorTo configures Compile to write the root schema and every
+// transitively resolved remote import into vendorDir, with their import
+// statements rewritten to reference the vendored copies, so the result can
+// be recompiled offline from a pinned set of schemas.
+func VendorTo(vendorDir string) Option {
+	return func(cfg *config) { cfg.vendorDir = &vendorDir }
+}
+
+// ImportTimeout bounds how long Compile will spend resolving remote
+// imports (across the whole transitive import graph) before failing the
+// compile, protecting against a slow or unreachable ImportResolver
+// hanging indefinitely. Only meaningful when combined with ImportsVia.
+func ImportTimeout(d time.Duration) Option {
+	return func(cfg *config) { cfg.importTimeout = d }
+}
+
 type Option func(*config)
 
 type ObjectPrefixOption func(*config)
@@ -98,6 +143,13 @@ type compilationContext struct {
 	// NOTE: This depends on an assumption that a depth-first search will always
 	// find a cycle, even if we're otherwise marking globally visited nodes.
 	locallyVisitedFiles *mapz.Set[string]
+	// importResolver resolves non-relative import paths. May be nil.
+	importResolver ImportResolver
+	// resolvedImports caches resolved remote schemas, keyed by the canonical
+	// URL+digest computed by canonicalImportKey, so the same remote import
+	// reached via two different paths is only fetched once and still
+	// participates in globallyVisitedFiles cycle/duplicate detection.
+	resolvedImports *resolvedImportCache
 }
 
 // Compile compilers the input schema into a set of namespace definition protos.
@@ -106,6 +158,7 @@ func Compile(schema InputSchema, prefix ObjectPrefixOption, opts ...Option) (*Co
 		existingNames:        mapz.NewSet[string](),
 		globallyVisitedFiles: mapz.NewSet[string](),
 		locallyVisitedFiles:  mapz.NewSet[string](),
+		resolvedImports:      newResolvedImportCache(),
 	}
 	return compileImpl(schema, cctx, prefix, opts...)
 }
@@ -118,6 +171,24 @@ func compileImpl(schema InputSchema, cctx compilationContext, prefix ObjectPrefi
 		fn(cfg)
 	}
 
+	cctx.importResolver = cfg.importResolver
+	originalSchemaString := schema.SchemaString
+
+	if cfg.importResolver != nil {
+		importTimeout := cfg.importTimeout
+		if importTimeout <= 0 {
+			importTimeout = defaultImportTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+		resolved, err := resolveRemoteImports(ctx, &cctx, schema.SchemaString, map[string]bool{})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		schema.SchemaString = resolved
+	}
+
 	mapper := newPositionMapper(schema)
 	root := parser.Parse(createAstNode, schema.Source, schema.SchemaString).(*dslNode)
 	errs := root.FindAll(dslshape.NodeTypeError)
@@ -135,6 +206,8 @@ func compileImpl(schema InputSchema, cctx compilationContext, prefix ObjectPrefi
 		existingNames:        cctx.existingNames,
 		locallyVisitedFiles:  cctx.locallyVisitedFiles,
 		globallyVisitedFiles: cctx.globallyVisitedFiles,
+		importResolver:       cctx.importResolver,
+		resolvedImports:      cctx.resolvedImports,
 	}, root)
 	if err != nil {
 		var errorWithNode errorWithNode
@@ -145,9 +218,77 @@ func compileImpl(schema InputSchema, cctx compilationContext, prefix ObjectPrefi
 		return nil, err
 	}
 
+	if cfg.vendorDir != nil {
+		if err := writeVendorDirectory(context.Background(), *cfg.vendorDir, originalSchemaString, cctx.resolvedImports); err != nil {
+			return nil, fmt.Errorf("failed to vendor imports: %w", err)
+		}
+	}
+
 	return compiled, nil
 }
 
+// remoteImportPattern matches a schema-level `import "<scheme>://..."`
+// statement, capturing the import path. Plain SourceFolder-relative
+// imports (no scheme) are left untouched for the existing import
+// resolution to handle.
+var remoteImportPattern = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+"([a-zA-Z][a-zA-Z0-9+.-]*://[^"]+)"[ \t]*\r?\n?`)
+
+// resolveRemoteImports rewrites every remote import statement in
+// schemaString into the (recursively resolved) contents of the imported
+// schema, inlined in place of the import statement. Resolved schemas are
+// cached via cctx.resolvedImports so the same import reached from two
+// places is only fetched once, and importStack detects circular imports
+// within a single recursive chain.
+func resolveRemoteImports(ctx context.Context, cctx *compilationContext, schemaString string, importStack map[string]bool) (string, error) {
+	if cctx.importResolver == nil {
+		return schemaString, nil
+	}
+
+	var resolveErr error
+	resolved := remoteImportPattern.ReplaceAllStringFunc(schemaString, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+
+		submatches := remoteImportPattern.FindStringSubmatch(match)
+		importPath := submatches[1]
+
+		content, key, err := cctx.resolvedImports.getOrResolve(ctx, cctx.importResolver, importPath)
+		if err != nil {
+			resolveErr = fmt.Errorf("failed to resolve import %q: %w", importPath, err)
+			return match
+		}
+
+		if importStack[key] {
+			resolveErr = fmt.Errorf("circular import detected resolving %q", importPath)
+			return match
+		}
+
+		if cctx.globallyVisitedFiles.Has(key) {
+			// Already inlined elsewhere in this compile; skip re-inlining
+			// to avoid duplicate-definition errors.
+			return "\n"
+		}
+		cctx.globallyVisitedFiles.Add(key)
+
+		importStack[key] = true
+		nested, err := resolveRemoteImports(ctx, cctx, content.SchemaString, importStack)
+		delete(importStack, key)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+
+		return "\n" + nested + "\n"
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return resolved, nil
+}
+
 func errorNodeToError(node *dslNode, mapper input.PositionMapper) error {
 	if node.GetType() != dslshape.NodeTypeError {
 		return fmt.Errorf("given none error node")
@@ -0,0 +1,101 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/genutil/mapz"
+)
+
+type funcResolver struct {
+	fn func(ctx context.Context, importPath string) (InputSchema, error)
+}
+
+func (r funcResolver) Resolve(ctx context.Context, importPath string) (InputSchema, error) {
+	return r.fn(ctx, importPath)
+}
+
+func newTestCompilationContext(resolver ImportResolver) *compilationContext {
+	return &compilationContext{
+		existingNames:        mapz.NewSet[string](),
+		globallyVisitedFiles: mapz.NewSet[string](),
+		locallyVisitedFiles:  mapz.NewSet[string](),
+		importResolver:       resolver,
+		resolvedImports:      newResolvedImportCache(),
+	}
+}
+
+func TestResolveRemoteImportsInlinesImport(t *testing.T) {
+	resolver := funcResolver{fn: func(_ context.Context, importPath string) (InputSchema, error) {
+		require.Equal(t, "https://example.com/base.zed", importPath)
+		return InputSchema{SchemaString: "definition user {}"}, nil
+	}}
+
+	cctx := newTestCompilationContext(resolver)
+
+	schema := "import \"https://example.com/base.zed\"\ndefinition document {}\n"
+	resolved, err := resolveRemoteImports(context.Background(), cctx, schema, map[string]bool{})
+	require.NoError(t, err)
+
+	require.NotContains(t, resolved, "import \"https://example.com/base.zed\"")
+	require.Contains(t, resolved, "definition user {}")
+	require.Contains(t, resolved, "definition document {}")
+}
+
+func TestResolveRemoteImportsDetectsCircularImport(t *testing.T) {
+	resolver := funcResolver{fn: func(_ context.Context, importPath string) (InputSchema, error) {
+		return InputSchema{SchemaString: "import \"https://example.com/a.zed\"\n"}, nil
+	}}
+
+	cctx := newTestCompilationContext(resolver)
+
+	schema := "import \"https://example.com/a.zed\"\n"
+	_, err := resolveRemoteImports(context.Background(), cctx, schema, map[string]bool{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "circular import")
+}
+
+func TestResolveRemoteImportsPropagatesContextTimeout(t *testing.T) {
+	resolver := funcResolver{fn: func(ctx context.Context, _ string) (InputSchema, error) {
+		<-ctx.Done()
+		return InputSchema{}, ctx.Err()
+	}}
+
+	cctx := newTestCompilationContext(resolver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	schema := "import \"https://example.com/slow.zed\"\n"
+	_, err := resolveRemoteImports(ctx, cctx, schema, map[string]bool{})
+	require.Error(t, err)
+}
+
+func TestWriteVendorDirectoryRewritesImports(t *testing.T) {
+	resolver := funcResolver{fn: func(_ context.Context, importPath string) (InputSchema, error) {
+		return InputSchema{SchemaString: "definition user {}"}, nil
+	}}
+
+	cctx := newTestCompilationContext(resolver)
+
+	rootSchema := "import \"https://example.com/base.zed\"\ndefinition document {}\n"
+	_, err := resolveRemoteImports(context.Background(), cctx, rootSchema, map[string]bool{})
+	require.NoError(t, err)
+
+	vendorDir := t.TempDir()
+	require.NoError(t, writeVendorDirectory(context.Background(), vendorDir, rootSchema, cctx.resolvedImports))
+
+	rootContents, err := os.ReadFile(filepath.Join(vendorDir, vendoredRootFileName))
+	require.NoError(t, err)
+	require.NotContains(t, string(rootContents), "https://example.com/base.zed")
+	require.Contains(t, string(rootContents), vendoredFileName("https://example.com/base.zed"))
+
+	vendoredContents, err := os.ReadFile(filepath.Join(vendorDir, vendoredFileName("https://example.com/base.zed")))
+	require.NoError(t, err)
+	require.Equal(t, "definition user {}", string(vendoredContents))
+}
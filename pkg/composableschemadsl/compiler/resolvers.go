@@ -0,0 +1,314 @@
+package compiler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/authzed/spicedb/pkg/composableschemadsl/input"
+)
+
+// ImportResolver resolves a schema `import` path that is not a plain
+// SourceFolder-relative file reference into its contents. Implementations
+// are expected to be safe for concurrent use.
+type ImportResolver interface {
+	// Resolve fetches the schema referenced by importPath and returns its
+	// contents. importPath is the raw string following the `import`
+	// keyword, e.g. `https://example.com/schema.zed#sha256=abcd...` or
+	// `git+https://github.com/org/repo.git@v1/schema.zed`.
+	Resolve(ctx context.Context, importPath string) (InputSchema, error)
+}
+
+// CompositeResolver dispatches to one of several ImportResolvers based on
+// the importPath's scheme prefix (file://, https://, git+https://, ...).
+// Unrecognized schemes result in an error, rather than silently falling
+// back to the local filesystem.
+type CompositeResolver struct {
+	resolvers map[string]ImportResolver
+}
+
+// NewCompositeResolver builds a CompositeResolver from a set of resolvers
+// keyed by the scheme prefix they handle (e.g. "file://", "https://").
+func NewCompositeResolver(resolvers map[string]ImportResolver) *CompositeResolver {
+	return &CompositeResolver{resolvers: resolvers}
+}
+
+// NewDefaultResolver builds a CompositeResolver wired with the built-in
+// file://, https://, and git+https:// resolvers. The https:// resolver
+// uses a client that refuses to follow redirects, since a redirect could
+// otherwise be used to smuggle a request past an importPath that looked
+// safe to a caller-supplied allowlist.
+func NewDefaultResolver() *CompositeResolver {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return NewCompositeResolver(map[string]ImportResolver{
+		"file://":      FileResolver{},
+		"https://":     HTTPSResolver{Client: client},
+		"git+https://": GitResolver{},
+	})
+}
+
+func (r *CompositeResolver) Resolve(ctx context.Context, importPath string) (InputSchema, error) {
+	for scheme, resolver := range r.resolvers {
+		if strings.HasPrefix(importPath, scheme) {
+			return resolver.Resolve(ctx, importPath)
+		}
+	}
+	return InputSchema{}, fmt.Errorf("no import resolver registered for %q", importPath)
+}
+
+// FileResolver resolves `file://` imports from the local filesystem.
+type FileResolver struct{}
+
+func (FileResolver) Resolve(_ context.Context, importPath string) (InputSchema, error) {
+	path := strings.TrimPrefix(importPath, "file://")
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("could not read imported schema %q: %w", importPath, err)
+	}
+
+	return InputSchema{
+		Source:       input.Source(importPath),
+		SchemaString: string(contents),
+	}, nil
+}
+
+// maxImportResponseBytes bounds how much of a remote import's body
+// HTTPSResolver will read, so a malicious or misbehaving server can't
+// exhaust memory by returning an unbounded (or infinite) response.
+const maxImportResponseBytes = 10 * 1024 * 1024 // 10 MiB
+
+// HTTPSResolver resolves `https://` imports over HTTP(S), optionally
+// pinning the expected content with a `#sha256=<hex>` fragment, e.g.
+// `https://example.com/schema.zed#sha256=9f86d08...`.
+type HTTPSResolver struct {
+	Client *http.Client
+}
+
+func (r HTTPSResolver) Resolve(ctx context.Context, importPath string) (InputSchema, error) {
+	url, expectedDigest, _ := strings.Cut(importPath, "#sha256=")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("invalid import URL %q: %w", importPath, err)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("could not fetch imported schema %q: %w", importPath, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return InputSchema{}, fmt.Errorf("fetching imported schema %q returned status %d", importPath, resp.StatusCode)
+	}
+
+	limited := io.LimitReader(resp.Body, maxImportResponseBytes+1)
+	contents, err := io.ReadAll(limited)
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("could not read imported schema %q: %w", importPath, err)
+	}
+	if len(contents) > maxImportResponseBytes {
+		return InputSchema{}, fmt.Errorf("imported schema %q exceeds the %d byte size limit", importPath, maxImportResponseBytes)
+	}
+
+	if expectedDigest != "" {
+		actualDigest := sha256Hex(contents)
+		if !strings.EqualFold(actualDigest, expectedDigest) {
+			return InputSchema{}, fmt.Errorf("imported schema %q failed SHA256 verification: expected %s, got %s", url, expectedDigest, actualDigest)
+		}
+	}
+
+	return InputSchema{
+		Source:       input.Source(importPath),
+		SchemaString: string(contents),
+	}, nil
+}
+
+// GitResolver resolves `git+https://host/repo.git@ref/path.zed` imports by
+// shallow-cloning the ref into a temporary directory and reading the
+// requested path out of it.
+type GitResolver struct{}
+
+func (GitResolver) Resolve(ctx context.Context, importPath string) (InputSchema, error) {
+	repoURL, ref, path, err := parseGitImportPath(importPath)
+	if err != nil {
+		return InputSchema{}, err
+	}
+
+	dir, err := os.MkdirTemp("", "spicedb-import-*")
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("could not create temp dir for git import %q: %w", importPath, err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--branch", ref, repoURL, dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return InputSchema{}, fmt.Errorf("could not clone %q at %q: %w: %s", repoURL, ref, err, out)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("could not read %q from %q at %q: %w", path, repoURL, ref, err)
+	}
+
+	return InputSchema{
+		Source:       input.Source(importPath),
+		SchemaString: string(contents),
+	}, nil
+}
+
+// parseGitImportPath splits `git+https://host/repo.git@ref/path.zed` into
+// its repository URL, ref, and in-repo path.
+func parseGitImportPath(importPath string) (repoURL, ref, path string, err error) {
+	rest := strings.TrimPrefix(importPath, "git+")
+
+	repoAndRest, pathPart, found := strings.Cut(rest, ".git@")
+	if !found {
+		return "", "", "", fmt.Errorf("git import %q must be of the form git+https://host/repo.git@ref/path.zed", importPath)
+	}
+
+	refPart, filePath, found := strings.Cut(pathPart, "/")
+	if !found {
+		return "", "", "", fmt.Errorf("git import %q is missing a path within the repository", importPath)
+	}
+
+	return repoAndRest + ".git", refPart, filePath, nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// resolvedImportCache caches resolved remote schemas keyed by their
+// canonical URL+digest, so that the same import reached via two different
+// paths in the dependency graph is only fetched once, and so the final
+// set can be written out by VendorTo.
+type resolvedImportCache struct {
+	mu       sync.Mutex
+	resolved map[string]InputSchema
+	order    []string
+}
+
+func newResolvedImportCache() *resolvedImportCache {
+	return &resolvedImportCache{resolved: map[string]InputSchema{}}
+}
+
+// getOrResolve returns the cached InputSchema for importPath, resolving
+// and caching it via resolver if this is the first time it's been seen.
+func (c *resolvedImportCache) getOrResolve(ctx context.Context, resolver ImportResolver, importPath string) (InputSchema, string, error) {
+	key := canonicalImportKey(importPath)
+
+	c.mu.Lock()
+	if cached, ok := c.resolved[key]; ok {
+		c.mu.Unlock()
+		return cached, key, nil
+	}
+	c.mu.Unlock()
+
+	resolved, err := resolver.Resolve(ctx, importPath)
+	if err != nil {
+		return InputSchema{}, "", err
+	}
+
+	c.mu.Lock()
+	if _, ok := c.resolved[key]; !ok {
+		c.resolved[key] = resolved
+		c.order = append(c.order, key)
+	}
+	c.mu.Unlock()
+
+	return resolved, key, nil
+}
+
+// canonicalImportKey returns the URL plus any `#sha256=...` pin, with the
+// digest lowercased, so references pinned to the same digest share a cache
+// entry while a pinned reference never reuses content fetched unpinned (or
+// under another digest) without its own SHA256 verification.
+func canonicalImportKey(importPath string) string {
+	url, digest, found := strings.Cut(importPath, "#sha256=")
+	if !found {
+		return url
+	}
+	return url + "#sha256=" + strings.ToLower(digest)
+}
+
+// vendoredRootFileName is the name the original (root) schema is written
+// under inside vendorDir, alongside its transitively vendored imports.
+const vendoredRootFileName = "root.zed"
+
+// writeVendorDirectory writes rootSchemaString and every schema resolved
+// through cache into vendorDir, one file per canonical import key, with
+// their remote import statements rewritten to reference the vendored
+// copies by name. This is what lets a schema compiled with
+// SourceFolder(vendorDir) and no ImportResolver reproduce the same
+// compilation offline.
+func writeVendorDirectory(_ context.Context, vendorDir string, rootSchemaString string, cache *resolvedImportCache) error {
+	if cache == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(vendorDir, 0o755); err != nil {
+		return fmt.Errorf("could not create vendor directory %q: %w", vendorDir, err)
+	}
+
+	rootPath := filepath.Join(vendorDir, vendoredRootFileName)
+	if err := os.WriteFile(rootPath, []byte(rewriteImportsForVendoring(rootSchemaString)), 0o644); err != nil {
+		return fmt.Errorf("could not write vendored root schema: %w", err)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for _, key := range cache.order {
+		resolved := cache.resolved[key]
+		vendoredPath := filepath.Join(vendorDir, vendoredFileName(key))
+		rewritten := rewriteImportsForVendoring(resolved.SchemaString)
+		if err := os.WriteFile(vendoredPath, []byte(rewritten), 0o644); err != nil {
+			return fmt.Errorf("could not write vendored schema for %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// rewriteImportsForVendoring rewrites every remote import statement in
+// schemaString into a plain SourceFolder-relative import naming the
+// vendored copy of that import, so the rewritten schema no longer depends
+// on an ImportResolver to compile.
+func rewriteImportsForVendoring(schemaString string) string {
+	return remoteImportPattern.ReplaceAllStringFunc(schemaString, func(match string) string {
+		submatches := remoteImportPattern.FindStringSubmatch(match)
+		key := canonicalImportKey(submatches[1])
+		return fmt.Sprintf("import %q\n", vendoredFileName(key))
+	})
+}
+
+// vendoredFileName turns a canonical import key into a stable, flat file
+// name safe to place directly inside the vendor directory.
+func vendoredFileName(canonicalKey string) string {
+	replacer := strings.NewReplacer("://", "_", "/", "_", "@", "_")
+	name := replacer.Replace(canonicalKey)
+	if !strings.HasSuffix(name, ".zed") {
+		name += ".zed"
+	}
+	return name
+}
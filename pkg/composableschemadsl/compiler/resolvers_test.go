@@ -0,0 +1,156 @@
+package compiler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileResolver(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.zed")
+	require.NoError(t, os.WriteFile(path, []byte("definition user {}"), 0o644))
+
+	schema, err := FileResolver{}.Resolve(context.Background(), "file://"+path)
+	require.NoError(t, err)
+	require.Equal(t, "definition user {}", schema.SchemaString)
+}
+
+func TestFileResolverMissingFile(t *testing.T) {
+	_, err := FileResolver{}.Resolve(context.Background(), "file:///does/not/exist.zed")
+	require.Error(t, err)
+}
+
+func TestHTTPSResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("definition user {}"))
+	}))
+	defer server.Close()
+
+	schema, err := HTTPSResolver{Client: server.Client()}.Resolve(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, "definition user {}", schema.SchemaString)
+}
+
+func TestHTTPSResolverSHA256Pinning(t *testing.T) {
+	const body = "definition user {}"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	validDigest := sha256Hex([]byte(body))
+
+	_, err := HTTPSResolver{Client: server.Client()}.Resolve(context.Background(), server.URL+"#sha256="+validDigest)
+	require.NoError(t, err)
+
+	_, err = HTTPSResolver{Client: server.Client()}.Resolve(context.Background(), server.URL+"#sha256=deadbeef")
+	require.Error(t, err)
+}
+
+func TestHTTPSResolverRejectsOversizedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, maxImportResponseBytes+1))
+	}))
+	defer server.Close()
+
+	_, err := HTTPSResolver{Client: server.Client()}.Resolve(context.Background(), server.URL)
+	require.Error(t, err)
+}
+
+func TestNewDefaultResolverDoesNotFollowRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("definition user {}"))
+	}))
+	defer target.Close()
+
+	redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer redirecting.Close()
+
+	resolver := NewDefaultResolver().resolvers["https://"].(HTTPSResolver)
+
+	// The client is configured not to follow redirects, so the 302 itself
+	// surfaces as a non-200 status rather than silently being followed to
+	// target.
+	_, err := resolver.Resolve(context.Background(), redirecting.URL)
+	require.Error(t, err)
+}
+
+func TestParseGitImportPath(t *testing.T) {
+	tcs := []struct {
+		importPath   string
+		expectedRepo string
+		expectedRef  string
+		expectedPath string
+		expectErr    bool
+	}{
+		{
+			importPath:   "git+https://github.com/org/repo.git@v1/schema.zed",
+			expectedRepo: "https://github.com/org/repo.git",
+			expectedRef:  "v1",
+			expectedPath: "schema.zed",
+		},
+		{
+			importPath:   "git+https://github.com/org/repo.git@main/nested/schema.zed",
+			expectedRepo: "https://github.com/org/repo.git",
+			expectedRef:  "main",
+			expectedPath: "nested/schema.zed",
+		},
+		{
+			importPath: "git+https://github.com/org/repo.git",
+			expectErr:  true,
+		},
+		{
+			importPath: "git+https://github.com/org/repo.git@v1",
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.importPath, func(t *testing.T) {
+			repoURL, ref, path, err := parseGitImportPath(tc.importPath)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRepo, repoURL)
+			require.Equal(t, tc.expectedRef, ref)
+			require.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
+
+func TestResolvedImportCacheDedupes(t *testing.T) {
+	cache := newResolvedImportCache()
+	calls := 0
+	resolver := &countingResolver{fn: func(ctx context.Context, importPath string) (InputSchema, error) {
+		calls++
+		return InputSchema{SchemaString: "definition user {}"}, nil
+	}}
+
+	_, key1, err := cache.getOrResolve(context.Background(), resolver, "https://example.com/schema.zed#sha256=abcd")
+	require.NoError(t, err)
+
+	_, key2, err := cache.getOrResolve(context.Background(), resolver, "https://example.com/schema.zed#sha256=ABCD")
+	require.NoError(t, err)
+
+	require.Equal(t, key1, key2)
+	require.Equal(t, 1, calls, "second resolve of the same canonical import should be served from cache")
+}
+
+type countingResolver struct {
+	fn func(ctx context.Context, importPath string) (InputSchema, error)
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, importPath string) (InputSchema, error) {
+	return r.fn(ctx, importPath)
+}
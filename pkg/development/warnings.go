@@ -0,0 +1,369 @@
+package development
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	developerv1 "github.com/authzed/spicedb/pkg/proto/developer/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// Severity classifies how seriously a lint rule's findings should be
+// treated. Rules default to SeverityWarn unless overridden by a
+// LintConfig.
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+// disablePragmaPrefix is the schema comment, placed on the line preceding
+// the construct it applies to, that suppresses a single named rule (or
+// every rule, via disableAllPragma) for that construct.
+//
+//	// spicedb:disable self-reference
+//	permission view = view
+const disablePragmaPrefix = "spicedb:disable"
+
+const disableAllPragma = "*"
+
+// LintContext is the input given to a LintRule: the namespace definition
+// currently being linted, plus every other namespace definition compiled
+// alongside it so rules can resolve cross-definition references (e.g.
+// arrows). Caveat definitions are not passed to rules and are not linted.
+type LintContext struct {
+	Definition     *core.NamespaceDefinition
+	AllDefinitions map[string]*core.NamespaceDefinition
+}
+
+// LintRule inspects a single compiled namespace definition and returns any
+// warnings it finds. Rules are registered via RegisterLintRule and run by
+// GetWarnings for every definition in the schema.
+type LintRule func(ctx *LintContext) ([]*developerv1.DeveloperWarning, error)
+
+var (
+	ruleRegistryMu sync.Mutex
+	ruleRegistry   = map[string]LintRule{}
+	ruleOrder      []string
+)
+
+// RegisterLintRule adds a named LintRule to the set run by GetWarnings.
+// Registering a rule under a name that is already registered replaces it.
+// Callers typically register custom rules from an init function.
+func RegisterLintRule(name string, rule LintRule) {
+	ruleRegistryMu.Lock()
+	defer ruleRegistryMu.Unlock()
+
+	if _, ok := ruleRegistry[name]; !ok {
+		ruleOrder = append(ruleOrder, name)
+	}
+	ruleRegistry[name] = rule
+}
+
+func init() {
+	RegisterLintRule("self-reference", selfReferencingPermissionRule)
+	RegisterLintRule("arrow-to-relation", arrowReferencesRelationRule)
+	RegisterLintRule("redundant-suffix", redundantNameSuffixRule)
+}
+
+// LintConfig maps rule names to the severity they should be reported at,
+// along with a set of rules to disable entirely. It is typically loaded
+// from a lint.yaml file checked into a schema's source tree via
+// LoadLintConfig.
+type LintConfig struct {
+	// Severities overrides the default severity for a named rule.
+	Severities map[string]Severity `yaml:"severities"`
+
+	// Disabled lists rule names that should never produce warnings,
+	// regardless of inline suppression pragmas.
+	Disabled []string `yaml:"disabled"`
+}
+
+// LoadLintConfig parses a lint.yaml document.
+func LoadLintConfig(data []byte) (*LintConfig, error) {
+	var config LintConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config: %w", err)
+	}
+	return &config, nil
+}
+
+func (c *LintConfig) isDisabled(rule string) bool {
+	if c == nil {
+		return false
+	}
+	for _, disabled := range c.Disabled {
+		if disabled == rule || disabled == disableAllPragma {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *LintConfig) severityFor(rule string, def Severity) Severity {
+	if c == nil {
+		return def
+	}
+	if sev, ok := c.Severities[rule]; ok {
+		return sev
+	}
+	return def
+}
+
+// GetWarnings runs every registered LintRule over the namespaces compiled
+// for devCtx, returning the warnings that survive inline
+// `spicedb:disable` suppression pragmas found in the schema source.
+func GetWarnings(ctx context.Context, devCtx *DevContext) ([]*developerv1.DeveloperWarning, error) {
+	return GetWarningsWithConfig(ctx, devCtx, nil)
+}
+
+// GetWarningsWithConfig is GetWarnings with an explicit LintConfig,
+// allowing callers to classify rules as info/warn/error or disable them
+// outright without relying on inline pragmas alone.
+func GetWarningsWithConfig(_ context.Context, devCtx *DevContext, lintConfig *LintConfig) ([]*developerv1.DeveloperWarning, error) {
+	suppressed := parseSuppressionPragmas(devCtx.Schema)
+
+	allDefs := make(map[string]*core.NamespaceDefinition, len(devCtx.Namespaces))
+	for _, ns := range devCtx.Namespaces {
+		allDefs[ns.Name] = ns
+	}
+
+	ruleRegistryMu.Lock()
+	names := append([]string(nil), ruleOrder...)
+	ruleRegistryMu.Unlock()
+
+	var warnings []*developerv1.DeveloperWarning
+	for _, ns := range devCtx.Namespaces {
+		lintCtx := &LintContext{Definition: ns, AllDefinitions: allDefs}
+
+		for _, name := range names {
+			if lintConfig.isDisabled(name) {
+				continue
+			}
+
+			ruleRegistryMu.Lock()
+			rule := ruleRegistry[name]
+			ruleRegistryMu.Unlock()
+
+			found, err := rule(lintCtx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, warning := range found {
+				if suppressed.has(warning.Line, name) {
+					continue
+				}
+				warning.Severity = string(lintConfig.severityFor(name, SeverityWarn))
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// suppressionSet records, per source line, the rule names disabled by a
+// `spicedb:disable` pragma on the line immediately prior.
+type suppressionSet map[int]map[string]struct{}
+
+func (s suppressionSet) has(line uint32, rule string) bool {
+	rules, ok := s[int(line)]
+	if !ok {
+		return false
+	}
+	if _, ok := rules[disableAllPragma]; ok {
+		return true
+	}
+	_, ok = rules[rule]
+	return ok
+}
+
+// parseSuppressionPragmas scans schema source for `spicedb:disable` line
+// comments and records which rule(s) they suppress on the following line,
+// matching the 1-indexed line numbers used by DeveloperWarning.
+func parseSuppressionPragmas(schema string) suppressionSet {
+	suppressed := suppressionSet{}
+
+	lines := strings.Split(schema, "\n")
+	for i, line := range lines {
+		idx := strings.Index(line, disablePragmaPrefix)
+		if idx < 0 {
+			continue
+		}
+
+		rest := strings.TrimSpace(line[idx+len(disablePragmaPrefix):])
+		rule := disableAllPragma
+		if rest != "" {
+			rule = rest
+		}
+
+		suppressedLine := i + 2 // the pragma applies to the line that follows it (1-indexed).
+		if suppressed[suppressedLine] == nil {
+			suppressed[suppressedLine] = map[string]struct{}{}
+		}
+		suppressed[suppressedLine][rule] = struct{}{}
+	}
+
+	return suppressed
+}
+
+func selfReferencingPermissionRule(ctx *LintContext) ([]*developerv1.DeveloperWarning, error) {
+	var warnings []*developerv1.DeveloperWarning
+
+	for _, relation := range ctx.Definition.Relation {
+		if relation.UsersetRewrite == nil {
+			continue
+		}
+
+		if referencesRelation(relation.UsersetRewrite, relation.Name) {
+			warnings = append(warnings, &developerv1.DeveloperWarning{
+				Message: fmt.Sprintf("Permission %q references itself, which will cause an error to be raised due to infinite recursion", relation.Name),
+				Line:    relation.SourcePosition.GetZeroIndexedLineNumber() + 1,
+				Column:  relation.SourcePosition.GetZeroIndexedColumnPosition() + 1,
+			})
+		}
+	}
+
+	return warnings, nil
+}
+
+func redundantNameSuffixRule(ctx *LintContext) ([]*developerv1.DeveloperWarning, error) {
+	var warnings []*developerv1.DeveloperWarning
+
+	for _, relation := range ctx.Definition.Relation {
+		if relation.Name == ctx.Definition.Name || !strings.HasSuffix(relation.Name, ctx.Definition.Name) {
+			continue
+		}
+
+		warnings = append(warnings, &developerv1.DeveloperWarning{
+			Message: fmt.Sprintf("Permission %q references parent type %q in its name; it is recommended to drop the suffix", relation.Name, ctx.Definition.Name),
+			Line:    relation.SourcePosition.GetZeroIndexedLineNumber() + 1,
+			Column:  relation.SourcePosition.GetZeroIndexedColumnPosition() + 1,
+		})
+	}
+
+	return warnings, nil
+}
+
+func arrowReferencesRelationRule(ctx *LintContext) ([]*developerv1.DeveloperWarning, error) {
+	var warnings []*developerv1.DeveloperWarning
+
+	for _, relation := range ctx.Definition.Relation {
+		if relation.UsersetRewrite == nil {
+			continue
+		}
+
+		forEachArrow(relation.UsersetRewrite, func(ttu *core.TupleToUserset) {
+			tuplesetName := ttu.GetTupleset().GetRelation()
+			computedName := ttu.GetComputedUserset().GetRelation()
+
+			tuplesetRelation := findRelation(ctx.Definition, tuplesetName)
+			if tuplesetRelation == nil {
+				return
+			}
+
+			for _, allowed := range tuplesetRelation.GetTypeInformation().GetAllowedDirectRelations() {
+				if allowed.GetRelation() != "" && allowed.GetRelation() != tuple.Ellipsis {
+					warnings = append(warnings, &developerv1.DeveloperWarning{
+						Message: fmt.Sprintf("Arrow `%s->%s` under permission %q references relation %q that has relation %q on subject %q: *the subject relation will be ignored for the arrow*",
+							tuplesetName, computedName, relation.Name, tuplesetName, allowed.GetRelation(), allowed.Namespace),
+						Line:   relation.SourcePosition.GetZeroIndexedLineNumber() + 1,
+						Column: relation.SourcePosition.GetZeroIndexedColumnPosition() + 1,
+					})
+					continue
+				}
+
+				subjectDef, ok := ctx.AllDefinitions[allowed.Namespace]
+				if !ok {
+					continue
+				}
+
+				target := findRelation(subjectDef, computedName)
+				if target == nil {
+					warnings = append(warnings, &developerv1.DeveloperWarning{
+						Message: fmt.Sprintf("Arrow `%s->%s` under permission %q references relation/permission %q that does not exist on any subject types of relation %q",
+							tuplesetName, computedName, relation.Name, computedName, tuplesetName),
+						Line:   relation.SourcePosition.GetZeroIndexedLineNumber() + 1,
+						Column: relation.SourcePosition.GetZeroIndexedColumnPosition() + 1,
+					})
+					continue
+				}
+
+				if target.UsersetRewrite == nil {
+					warnings = append(warnings, &developerv1.DeveloperWarning{
+						Message: fmt.Sprintf("Arrow `%s->%s` under permission %q references relation %q on definition %q; it is recommended to point to a permission",
+							tuplesetName, computedName, relation.Name, computedName, allowed.Namespace),
+						Line:   relation.SourcePosition.GetZeroIndexedLineNumber() + 1,
+						Column: relation.SourcePosition.GetZeroIndexedColumnPosition() + 1,
+					})
+				}
+			}
+		})
+	}
+
+	return warnings, nil
+}
+
+func findRelation(def *core.NamespaceDefinition, name string) *core.Relation {
+	for _, relation := range def.GetRelation() {
+		if relation.Name == name {
+			return relation
+		}
+	}
+	return nil
+}
+
+func forEachArrow(rewrite *core.UsersetRewrite, visit func(*core.TupleToUserset)) {
+	var children []*core.SetOperation_Child
+	switch op := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		children = op.Union.Child
+	case *core.UsersetRewrite_Intersection:
+		children = op.Intersection.Child
+	case *core.UsersetRewrite_Exclusion:
+		children = op.Exclusion.Child
+	}
+
+	for _, child := range children {
+		switch op := child.ChildType.(type) {
+		case *core.SetOperation_Child_TupleToUserset:
+			visit(op.TupleToUserset)
+		case *core.SetOperation_Child_UsersetRewrite:
+			forEachArrow(op.UsersetRewrite, visit)
+		}
+	}
+}
+
+func referencesRelation(rewrite *core.UsersetRewrite, name string) bool {
+	var children []*core.SetOperation_Child
+	switch op := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		children = op.Union.Child
+	case *core.UsersetRewrite_Intersection:
+		children = op.Intersection.Child
+	case *core.UsersetRewrite_Exclusion:
+		children = op.Exclusion.Child
+	}
+
+	for _, child := range children {
+		switch op := child.ChildType.(type) {
+		case *core.SetOperation_Child_ComputedUserset:
+			if op.ComputedUserset.Relation == name {
+				return true
+			}
+		case *core.SetOperation_Child_UsersetRewrite:
+			if referencesRelation(op.UsersetRewrite, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -36,9 +36,10 @@ func TestWarnings(t *testing.T) {
 				permission view = view	
 			}`,
 			expectedWarning: &developerv1.DeveloperWarning{
-				Message: "Permission \"view\" references itself, which will cause an error to be raised due to infinite recursion",
-				Line:    2,
-				Column:  5,
+				Message:  "Permission \"view\" references itself, which will cause an error to be raised due to infinite recursion",
+				Line:     2,
+				Column:   5,
+				Severity: string(SeverityWarn),
 			},
 		},
 		{
@@ -46,12 +47,13 @@ func TestWarnings(t *testing.T) {
 			schema: `definition test {
 				relation viewer: test
 				relation editor: test
-				permission view = viewer + (editor & view)	
+				permission view = viewer + (editor & view)
 			}`,
 			expectedWarning: &developerv1.DeveloperWarning{
-				Message: "Permission \"view\" references itself, which will cause an error to be raised due to infinite recursion",
-				Line:    4,
-				Column:  5,
+				Message:  "Permission \"view\" references itself, which will cause an error to be raised due to infinite recursion",
+				Line:     4,
+				Column:   5,
+				Severity: string(SeverityWarn),
 			},
 		},
 		{
@@ -68,9 +70,10 @@ func TestWarnings(t *testing.T) {
 			}
 			`,
 			expectedWarning: &developerv1.DeveloperWarning{
-				Message: "Arrow `group->member` under permission \"view\" references relation \"member\" on definition \"group\"; it is recommended to point to a permission",
-				Line:    9,
-				Column:  5,
+				Message:  "Arrow `group->member` under permission \"view\" references relation \"member\" on definition \"group\"; it is recommended to point to a permission",
+				Line:     9,
+				Column:   5,
+				Severity: string(SeverityWarn),
 			},
 		},
 		{
@@ -86,9 +89,10 @@ func TestWarnings(t *testing.T) {
 			}
 			`,
 			expectedWarning: &developerv1.DeveloperWarning{
-				Message: "Arrow `group->member` under permission \"view\" references relation/permission \"member\" that does not exist on any subject types of relation \"group\"",
-				Line:    8,
-				Column:  5,
+				Message:  "Arrow `group->member` under permission \"view\" references relation/permission \"member\" that does not exist on any subject types of relation \"group\"",
+				Line:     8,
+				Column:   5,
+				Severity: string(SeverityWarn),
 			},
 		},
 		{
@@ -106,9 +110,10 @@ func TestWarnings(t *testing.T) {
 			}
 			`,
 			expectedWarning: &developerv1.DeveloperWarning{
-				Message: "Arrow `parent_group->member` under permission \"view\" references relation \"parent_group\" that has relation \"member\" on subject \"group\": *the subject relation will be ignored for the arrow*",
-				Line:    10,
-				Column:  5,
+				Message:  "Arrow `parent_group->member` under permission \"view\" references relation \"parent_group\" that has relation \"member\" on subject \"group\": *the subject relation will be ignored for the arrow*",
+				Line:     10,
+				Column:   5,
+				Severity: string(SeverityWarn),
 			},
 		},
 		{
@@ -120,9 +125,10 @@ func TestWarnings(t *testing.T) {
 				permission view_document = viewer
 			}`,
 			expectedWarning: &developerv1.DeveloperWarning{
-				Message: "Permission \"view_document\" references parent type \"document\" in its name; it is recommended to drop the suffix",
-				Line:    5,
-				Column:  5,
+				Message:  "Permission \"view_document\" references parent type \"document\" in its name; it is recommended to drop the suffix",
+				Line:     5,
+				Column:   5,
+				Severity: string(SeverityWarn),
 			},
 		},
 	}
@@ -147,3 +153,40 @@ func TestWarnings(t *testing.T) {
 		})
 	}
 }
+
+func TestWarningSuppressionPragma(t *testing.T) {
+	schema := `definition test {
+		// spicedb:disable self-reference
+		permission view = view
+	}`
+
+	devCtx, devErr, err := NewDevContext(context.Background(), &developerv1.RequestContext{
+		Schema: schema,
+	})
+	require.NoError(t, err)
+	require.Empty(t, devErr)
+
+	warnings, err := GetWarnings(context.Background(), devCtx)
+	require.NoError(t, err)
+	require.Empty(t, warnings, "suppressed rule should not produce a warning")
+}
+
+func TestWarningSeverityOverride(t *testing.T) {
+	schema := `definition test {
+		permission view = view
+	}`
+
+	devCtx, devErr, err := NewDevContext(context.Background(), &developerv1.RequestContext{
+		Schema: schema,
+	})
+	require.NoError(t, err)
+	require.Empty(t, devErr)
+
+	lintConfig, err := LoadLintConfig([]byte("severities:\n  self-reference: error\n"))
+	require.NoError(t, err)
+
+	warnings, err := GetWarningsWithConfig(context.Background(), devCtx, lintConfig)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Equal(t, string(SeverityError), warnings[0].Severity)
+}
@@ -0,0 +1,63 @@
+// Package developerv1 mirrors the messages declared in developer.proto as
+// plain Go structs. It is hand-maintained, not generated: it has no
+// proto.Message method set (Reset/String/ProtoReflect/descriptor), so it
+// cannot be marshaled through real protobuf, grpc-gateway, or jsonpb, and
+// must not be treated as a wire-compatible proto type. Keep it in sync
+// with developer.proto by hand until it is regenerated with `buf generate`.
+package developerv1
+
+// RequestContext holds the schema and relationships used to drive the
+// developer playground and schema-check APIs.
+type RequestContext struct {
+	Schema string `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+}
+
+func (x *RequestContext) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+// DeveloperWarning is a single warning raised by the schema linter,
+// pointing at the 1-indexed line/column that produced it.
+type DeveloperWarning struct {
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Line    uint32 `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	Column  uint32 `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`
+
+	// Severity classifies how seriously this warning should be treated by
+	// callers (e.g. "info", "warn", "error"). development.GetWarnings sets it
+	// to "warn" (development.SeverityWarn) unless a LintConfig overrides the
+	// rule's severity; the field itself has no default and reads as "" when
+	// unset.
+	Severity string `protobuf:"bytes,4,opt,name=severity,proto3" json:"severity,omitempty"`
+}
+
+func (x *DeveloperWarning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeveloperWarning) GetLine() uint32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *DeveloperWarning) GetColumn() uint32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *DeveloperWarning) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}